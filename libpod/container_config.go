@@ -0,0 +1,49 @@
+package libpod
+
+import (
+	"github.com/containers/image/v5/manifest"
+)
+
+// ContainerConfig holds the fields of a container's on-disk configuration
+// that the healthcheck code in this package depends on. The remainder of
+// ContainerConfig is defined alongside the rest of the Container type and
+// is not part of this checkout.
+type ContainerConfig struct {
+	// RootfsImageName is the name of the image the container was created
+	// from
+	RootfsImageName string
+	// StopTimeout is the number of seconds to wait for the container to
+	// stop before sending SIGKILL
+	StopTimeout uint
+	// HealthCheckConfig is the healthcheck configuration parsed from the
+	// image or the --healthcheck-* create flags
+	HealthCheckConfig *manifest.Schema2HealthConfig
+	// HealthCheckOnFailureAction is the action to take once the
+	// container's healthcheck has failed enough times in a row to flip
+	// it to unhealthy. Populated from the --health-on-failure create/run
+	// flag via ParseHealthCheckOnFailureAction.
+	HealthCheckOnFailureAction HealthCheckOnFailureAction
+	// HealthCheckOnFailureCommand is the command to exec inside the
+	// container when HealthCheckOnFailureAction is
+	// HealthCheckOnFailureActionExec
+	HealthCheckOnFailureCommand []string
+	// HealthCheckHTTPHeaders are extra "Key: Value" headers sent with an
+	// HTTP healthcheck probe, populated from repeated
+	// --health-http-header create/run flags via
+	// ParseHealthCheckHTTPHeaders.
+	HealthCheckHTTPHeaders []string
+	// HealthCheckHTTPExpectedStatusMin and HealthCheckHTTPExpectedStatusMax
+	// bound the HTTP status codes an HTTP healthcheck probe treats as
+	// success, populated from --health-http-expect-status via
+	// ParseHealthCheckHTTPExpectedStatusRange. Both zero means the
+	// built-in 200-399 range.
+	HealthCheckHTTPExpectedStatusMin int
+	HealthCheckHTTPExpectedStatusMax int
+	// HealthCheckHTTPTLS causes an HTTP healthcheck probe whose URL does
+	// not already request https to dial TLS anyway, and
+	// HealthCheckHTTPTLSSkipVerify disables certificate verification for
+	// it. Populated from --health-http-tls and
+	// --health-http-tls-skip-verify.
+	HealthCheckHTTPTLS           bool
+	HealthCheckHTTPTLSSkipVerify bool
+}
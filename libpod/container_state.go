@@ -0,0 +1,24 @@
+package libpod
+
+import (
+	"time"
+
+	current "github.com/containernetworking/cni/pkg/types/current"
+)
+
+// ContainerState holds the fields of a container's runtime state that the
+// healthcheck code in this package depends on. The remainder of
+// ContainerState is defined alongside the rest of the Container type and is
+// not part of this checkout.
+type ContainerState struct {
+	// StartedTime is the time the container was last started
+	StartedTime time.Time
+	// NetworkStatus holds the per-network CNI/netavark results (including
+	// assigned IPs) produced when the container's network was set up
+	NetworkStatus []*current.Result
+	// HealthCheckNextRun is the next time the in-process healthcheck
+	// scheduler (goHealthCheckScheduler) is due to run this container's
+	// healthcheck. It is persisted so scheduling survives a podman
+	// restart instead of waiting a full interval before resuming.
+	HealthCheckNextRun time.Time
+}
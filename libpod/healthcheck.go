@@ -3,18 +3,27 @@ package libpod
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/containers/libpod/libpod/events"
 	"github.com/containers/libpod/pkg/inspect"
-	"github.com/coreos/go-systemd/dbus"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // HealthCheckStatus represents the current state of a container
@@ -56,6 +65,53 @@ const (
 	HealthCheckStarting string = "starting"
 )
 
+// HealthCheckOnFailureAction describes what the runtime should do once a
+// container's healthcheck has failed enough times in a row to flip the
+// container to unhealthy
+type HealthCheckOnFailureAction int
+
+const (
+	// HealthCheckOnFailureActionNone takes no action once the container is
+	// marked unhealthy
+	HealthCheckOnFailureActionNone HealthCheckOnFailureAction = iota
+	// HealthCheckOnFailureActionKill kills the container once it is marked
+	// unhealthy
+	HealthCheckOnFailureActionKill
+	// HealthCheckOnFailureActionRestart restarts the container once it is
+	// marked unhealthy
+	HealthCheckOnFailureActionRestart
+	// HealthCheckOnFailureActionStop stops the container once it is marked
+	// unhealthy
+	HealthCheckOnFailureActionStop
+	// HealthCheckOnFailureActionExec runs a configured command inside the
+	// container once it is marked unhealthy
+	HealthCheckOnFailureActionExec
+)
+
+// ParseHealthCheckOnFailureAction validates the value of the
+// --health-on-failure create/run flag and splits out the command to run
+// for the "exec" action. cmd/podman's container create path calls this to
+// populate ContainerConfig.HealthCheckOnFailureAction.
+func ParseHealthCheckOnFailureAction(action string, execCommand []string) (HealthCheckOnFailureAction, []string, error) {
+	switch action {
+	case "", "none":
+		return HealthCheckOnFailureActionNone, nil, nil
+	case "kill":
+		return HealthCheckOnFailureActionKill, nil, nil
+	case "stop":
+		return HealthCheckOnFailureActionStop, nil, nil
+	case "restart":
+		return HealthCheckOnFailureActionRestart, nil, nil
+	case "exec":
+		if len(execCommand) == 0 {
+			return HealthCheckOnFailureActionNone, nil, errors.Errorf("--health-on-failure=exec requires a command")
+		}
+		return HealthCheckOnFailureActionExec, execCommand, nil
+	default:
+		return HealthCheckOnFailureActionNone, nil, errors.Errorf("unknown --health-on-failure action %q", action)
+	}
+}
+
 // hcWriteCloser allows us to use bufio as a WriteCloser
 type hcWriteCloser struct {
 	*bufio.Writer
@@ -75,48 +131,34 @@ func (r *Runtime) HealthCheck(name string) (HealthCheckStatus, error) {
 	}
 	hcStatus, err := checkHealthCheckCanBeRun(container)
 	if err == nil {
-		return container.runHealthCheck()
+		return r.healthCheckPool.run(container)
 	}
 	return hcStatus, err
 }
 
+// probeResult is the outcome of a single healthcheck probe execution,
+// regardless of which probe kind (CMD, HTTP, TCP, GRPC) produced it
+type probeResult struct {
+	exitCode int
+	output   string
+}
+
 // runHealthCheck runs the health check as defined by the container
 func (c *Container) runHealthCheck() (HealthCheckStatus, error) {
-	var (
-		newCommand    []string
-		returnCode    int
-		capture       bytes.Buffer
-		inStartPeriod bool
-	)
+	var inStartPeriod bool
 	hcStatus, err := checkHealthCheckCanBeRun(c)
 	if err != nil {
 		return hcStatus, err
 	}
-	hcCommand := c.HealthCheckConfig().Test
-	if len(hcCommand) > 0 && hcCommand[0] == "CMD-SHELL" {
-		newCommand = []string{"sh", "-c", strings.Join(hcCommand[1:], " ")}
-	} else {
-		newCommand = hcCommand
-	}
-	captureBuffer := bufio.NewWriter(&capture)
-	hcw := hcWriteCloser{
-		captureBuffer,
-	}
-	streams := new(AttachStreams)
-	streams.OutputStream = hcw
-	streams.ErrorStream = hcw
-	streams.InputStream = os.Stdin
-	streams.AttachOutput = true
-	streams.AttachError = true
-	streams.AttachInput = true
 
-	logrus.Debugf("executing health check command %s for %s", strings.Join(newCommand, " "), c.ID())
 	timeStart := time.Now()
 	hcResult := HealthCheckSuccess
-	hcErr := c.Exec(false, false, []string{}, newCommand, "", "", streams, 0)
+	result, hcErr := c.runHealthCheckProbe()
+	if result == nil {
+		result = &probeResult{exitCode: 1}
+	}
 	if hcErr != nil {
 		hcResult = HealthCheckFailure
-		returnCode = 1
 	}
 	timeEnd := time.Now()
 	if c.HealthCheckConfig().StartPeriod > 0 {
@@ -129,23 +171,315 @@ func (c *Container) runHealthCheck() (HealthCheckStatus, error) {
 		}
 	}
 
-	eventLog := capture.String()
+	eventLog := result.output
 	if len(eventLog) > MaxHealthCheckLogLength {
 		eventLog = eventLog[:MaxHealthCheckLogLength]
 	}
 
 	if timeEnd.Sub(timeStart) > c.HealthCheckConfig().Timeout {
-		returnCode = -1
+		result.exitCode = -1
 		hcResult = HealthCheckFailure
 		hcErr = errors.Errorf("healthcheck command exceeded timeout of %s", c.HealthCheckConfig().Timeout.String())
 	}
-	hcl := newHealthCheckLog(timeStart, timeEnd, returnCode, eventLog)
+	recordHealthCheckRun(c, hcResult, timeEnd.Sub(timeStart))
+
+	hcl := newHealthCheckLog(timeStart, timeEnd, result.exitCode, eventLog)
 	if err := c.updateHealthCheckLog(hcl, inStartPeriod); err != nil {
 		return hcResult, errors.Wrapf(err, "unable to update health check log %s for %s", c.healthCheckLogPath(), c.ID())
 	}
 	return hcResult, hcErr
 }
 
+// healthCheckProbeKind returns which probe kind a Test slice's leading
+// element selects: "HTTP", "TCP" or "GRPC" for the dedicated probe types,
+// "CMD" for everything else (CMD, CMD-SHELL, or no recognized keyword,
+// which runExecHealthCheck treats as a raw command). Returns "" for an
+// empty Test slice.
+func healthCheckProbeKind(hcCommand []string) string {
+	if len(hcCommand) == 0 {
+		return ""
+	}
+	switch strings.ToUpper(hcCommand[0]) {
+	case "HTTP", "TCP", "GRPC":
+		return strings.ToUpper(hcCommand[0])
+	default:
+		return "CMD"
+	}
+}
+
+// runHealthCheckProbe dispatches to the probe implementation matching the
+// container's configured healthcheck kind. CMD and CMD-SHELL exec inside
+// the container as before; HTTP, TCP and GRPC dial the container's network
+// namespace directly from the runtime side
+func (c *Container) runHealthCheckProbe() (*probeResult, error) {
+	hcCommand := c.HealthCheckConfig().Test
+	switch healthCheckProbeKind(hcCommand) {
+	case "":
+		return nil, errors.Errorf("container %s has no healthcheck test configured", c.ID())
+	case "HTTP":
+		return c.runHTTPHealthCheck(hcCommand[1:])
+	case "TCP":
+		return c.runTCPHealthCheck(hcCommand[1:])
+	case "GRPC":
+		return c.runGRPCHealthCheck(hcCommand[1:])
+	default:
+		return c.runExecHealthCheck(hcCommand)
+	}
+}
+
+// runExecHealthCheck runs a CMD or CMD-SHELL healthcheck by exec'ing inside
+// the container
+func (c *Container) runExecHealthCheck(hcCommand []string) (*probeResult, error) {
+	var newCommand []string
+	if len(hcCommand) > 0 && hcCommand[0] == "CMD-SHELL" {
+		newCommand = []string{"sh", "-c", strings.Join(hcCommand[1:], " ")}
+	} else {
+		newCommand = hcCommand
+	}
+	var capture bytes.Buffer
+	captureBuffer := bufio.NewWriter(&capture)
+	hcw := hcWriteCloser{
+		captureBuffer,
+	}
+	streams := new(AttachStreams)
+	streams.OutputStream = hcw
+	streams.ErrorStream = hcw
+	streams.InputStream = os.Stdin
+	streams.AttachOutput = true
+	streams.AttachError = true
+	streams.AttachInput = true
+
+	logrus.Debugf("executing health check command %s for %s", strings.Join(newCommand, " "), c.ID())
+	hcErr := c.Exec(false, false, []string{}, newCommand, "", "", streams, 0)
+	result := &probeResult{output: capture.String()}
+	if hcErr != nil {
+		result.exitCode = 1
+	}
+	return result, hcErr
+}
+
+// execHealthCheckOnFailureCommand execs the configured
+// HealthCheckOnFailureCommand inside the container. It reuses the same
+// attach plumbing as the CMD/CMD-SHELL probe so the command's output ends
+// up in the container's normal logs rather than the healthcheck log.
+func (c *Container) execHealthCheckOnFailureCommand() error {
+	streams := new(AttachStreams)
+	streams.OutputStream = os.Stdout
+	streams.ErrorStream = os.Stderr
+	streams.InputStream = os.Stdin
+	streams.AttachOutput = true
+	streams.AttachError = true
+	streams.AttachInput = true
+
+	logrus.Debugf("executing healthcheck on-failure command %s for %s", strings.Join(c.config.HealthCheckOnFailureCommand, " "), c.ID())
+	return c.Exec(false, false, []string{}, c.config.HealthCheckOnFailureCommand, "", "", streams, 0)
+}
+
+// containerHealthCheckAddress returns the IP address of the container's
+// primary network interface so that HTTP/TCP/GRPC probes can dial into the
+// container's network namespace directly, without the overhead of an exec
+func (c *Container) containerHealthCheckAddress() (string, error) {
+	for _, result := range c.state.NetworkStatus {
+		for _, ip := range result.IPs {
+			return ip.Address.IP.String(), nil
+		}
+	}
+	return "", errors.Errorf("unable to determine an IP address for container %s health check probe", c.ID())
+}
+
+// rewriteHealthCheckHostlessURL rewrites a probe URL with no host (the
+// "http://:8080/healthz" convention for "this container's own address") to
+// point at containerAddr instead. URLs that already name a host are left
+// untouched.
+func rewriteHealthCheckHostlessURL(target *url.URL, containerAddr string) {
+	if target.Hostname() == "" {
+		target.Host = net.JoinHostPort(containerAddr, target.Port())
+	}
+}
+
+// ParseHealthCheckHTTPHeaders parses "Key: Value" strings from repeated
+// --health-http-header create/run flags into the headers an HTTP
+// healthcheck probe sends.
+func ParseHealthCheckHTTPHeaders(headers []string) (http.Header, error) {
+	parsed := make(http.Header, len(headers))
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid --health-http-header %q, expected \"Key: Value\"", header)
+		}
+		parsed.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return parsed, nil
+}
+
+// ParseHealthCheckHTTPExpectedStatusRange parses the "min-max" (or bare
+// "status") syntax of --health-http-expect-status, e.g. "200-299" or "204",
+// into inclusive bounds. An empty string returns 0, 0, nil, meaning "use
+// the default 200-399 range".
+func ParseHealthCheckHTTPExpectedStatusRange(statusRange string) (int, int, error) {
+	if statusRange == "" {
+		return 0, 0, nil
+	}
+	parts := strings.SplitN(statusRange, "-", 2)
+	min, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid --health-http-expect-status %q", statusRange)
+	}
+	max := min
+	if len(parts) == 2 {
+		if max, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, errors.Errorf("invalid --health-http-expect-status %q", statusRange)
+		}
+	}
+	if min > max {
+		return 0, 0, errors.Errorf("invalid --health-http-expect-status %q: min greater than max", statusRange)
+	}
+	return min, max, nil
+}
+
+// healthCheckHTTPExpectedStatusRange returns the inclusive HTTP status code
+// range an HTTP healthcheck probe treats as success, defaulting to 200-399
+// when HealthCheckHTTPExpectedStatusMin/Max are both unset.
+func (c *Container) healthCheckHTTPExpectedStatusRange() (int, int) {
+	min, max := c.config.HealthCheckHTTPExpectedStatusMin, c.config.HealthCheckHTTPExpectedStatusMax
+	if min == 0 && max == 0 {
+		return 200, 399
+	}
+	return min, max
+}
+
+// runHTTPHealthCheck runs an HTTP healthcheck probe. hcArgs is the Test
+// slice with the leading "HTTP" element stripped, e.g.
+// ["GET", "http://:8080/healthz"]
+func (c *Container) runHTTPHealthCheck(hcArgs []string) (*probeResult, error) {
+	if len(hcArgs) < 2 {
+		return nil, errors.Errorf("HTTP healthcheck for %s requires a method and a URL", c.ID())
+	}
+	method := hcArgs[0]
+	target, err := url.Parse(hcArgs[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid HTTP healthcheck URL for %s", c.ID())
+	}
+	if c.config.HealthCheckHTTPTLS && target.Scheme == "http" {
+		target.Scheme = "https"
+	}
+	if target.Hostname() == "" {
+		addr, err := c.containerHealthCheckAddress()
+		if err != nil {
+			return nil, err
+		}
+		rewriteHealthCheckHostlessURL(target, addr)
+	}
+
+	req, err := http.NewRequest(method, target.String(), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to build HTTP healthcheck request for %s", c.ID())
+	}
+	headers, err := ParseHealthCheckHTTPHeaders(c.config.HealthCheckHTTPHeaders)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range headers {
+		for _, value := range values {
+			// net/http ignores a Host entry in req.Header; it sends req.Host
+			if key == "Host" {
+				req.Host = value
+				continue
+			}
+			req.Header.Add(key, value)
+		}
+	}
+
+	client := &http.Client{Timeout: c.HealthCheckConfig().Timeout}
+	if target.Scheme == "https" {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: c.config.HealthCheckHTTPTLSSkipVerify}, // nolint:gosec
+		}
+	}
+
+	logrus.Debugf("executing HTTP health check %s %s for %s", method, target.String(), c.ID())
+	timeStart := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(timeStart)
+	if err != nil {
+		return &probeResult{exitCode: 1, output: fmt.Sprintf("HTTP %s %s failed after %s: %s", method, target.String(), latency, err)}, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, int64(MaxHealthCheckLogLength)))
+	output := fmt.Sprintf("HTTP %s %s -> %d (%s)\n%s", method, target.String(), resp.StatusCode, latency, string(body))
+	min, max := c.healthCheckHTTPExpectedStatusRange()
+	if resp.StatusCode < min || resp.StatusCode > max {
+		return &probeResult{exitCode: 1, output: output}, errors.Errorf("HTTP healthcheck for %s returned status %d, want %d-%d", c.ID(), resp.StatusCode, min, max)
+	}
+	return &probeResult{exitCode: 0, output: output}, nil
+}
+
+// runTCPHealthCheck runs a TCP connect healthcheck probe. hcArgs is the
+// Test slice with the leading "TCP" element stripped, e.g. [":8080"]
+func (c *Container) runTCPHealthCheck(hcArgs []string) (*probeResult, error) {
+	if len(hcArgs) < 1 {
+		return nil, errors.Errorf("TCP healthcheck for %s requires a port", c.ID())
+	}
+	port := strings.TrimPrefix(hcArgs[0], ":")
+	addr, err := c.containerHealthCheckAddress()
+	if err != nil {
+		return nil, err
+	}
+	address := net.JoinHostPort(addr, port)
+
+	logrus.Debugf("executing TCP health check against %s for %s", address, c.ID())
+	dialer := net.Dialer{Timeout: c.HealthCheckConfig().Timeout}
+	timeStart := time.Now()
+	conn, err := dialer.Dial("tcp", address)
+	latency := time.Since(timeStart)
+	if err != nil {
+		return &probeResult{exitCode: 1, output: fmt.Sprintf("TCP connect to %s failed after %s: %s", address, latency, err)}, err
+	}
+	conn.Close()
+	return &probeResult{exitCode: 0, output: fmt.Sprintf("TCP connect to %s succeeded (%s)", address, latency)}, nil
+}
+
+// runGRPCHealthCheck runs a grpc.health.v1 healthcheck probe. hcArgs is
+// the Test slice with the leading "GRPC" element stripped, e.g.
+// [":8080", "myservice"]
+func (c *Container) runGRPCHealthCheck(hcArgs []string) (*probeResult, error) {
+	if len(hcArgs) < 1 {
+		return nil, errors.Errorf("GRPC healthcheck for %s requires a port", c.ID())
+	}
+	port := strings.TrimPrefix(hcArgs[0], ":")
+	addr, err := c.containerHealthCheckAddress()
+	if err != nil {
+		return nil, err
+	}
+	var service string
+	if len(hcArgs) > 1 {
+		service = hcArgs[1]
+	}
+	address := net.JoinHostPort(addr, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.HealthCheckConfig().Timeout)
+	defer cancel()
+
+	logrus.Debugf("executing GRPC health check against %s (service %q) for %s", address, service, c.ID())
+	timeStart := time.Now()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return &probeResult{exitCode: 1, output: fmt.Sprintf("GRPC dial to %s failed after %s: %s", address, time.Since(timeStart), err)}, err
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	latency := time.Since(timeStart)
+	if err != nil {
+		return &probeResult{exitCode: 1, output: fmt.Sprintf("GRPC health check for service %q failed after %s: %s", service, latency, err)}, err
+	}
+	output := fmt.Sprintf("GRPC health check for service %q returned %s (%s)", service, resp.Status, latency)
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return &probeResult{exitCode: 1, output: output}, errors.Errorf("GRPC healthcheck for %s is not serving: %s", c.ID(), resp.Status)
+	}
+	return &probeResult{exitCode: 0, output: output}, nil
+}
+
 func checkHealthCheckCanBeRun(c *Container) (HealthCheckStatus, error) {
 	cstate, err := c.State()
 	if err != nil {
@@ -181,7 +515,8 @@ func (c *Container) updateHealthStatus(status string) error {
 	if err != nil {
 		return errors.Wrapf(err, "unable to marshall healthchecks for writing status")
 	}
-	return ioutil.WriteFile(c.healthCheckLogPath(), newResults, 0700)
+	defaultHealthCheckLogWriter.write(c.healthCheckLogPath(), newResults)
+	return nil
 }
 
 // UpdateHealthCheckLog parses the health check results and writes the log
@@ -190,6 +525,7 @@ func (c *Container) updateHealthCheckLog(hcl inspect.HealthCheckLog, inStartPeri
 	if err != nil {
 		return err
 	}
+	previousStatus := healthCheck.Status
 	if hcl.ExitCode == 0 {
 		//	set status to healthy, reset failing state to 0
 		healthCheck.Status = HealthCheckHealthy
@@ -215,7 +551,67 @@ func (c *Container) updateHealthCheckLog(hcl inspect.HealthCheckLog, inStartPeri
 	if err != nil {
 		return errors.Wrapf(err, "unable to marshall healthchecks for writing")
 	}
-	return ioutil.WriteFile(c.healthCheckLogPath(), newResults, 0700)
+	defaultHealthCheckLogWriter.write(c.healthCheckLogPath(), newResults)
+	recordHealthCheckStatus(c, healthCheck.Status, int(healthCheck.FailingStreak))
+
+	if healthCheck.Status != previousStatus {
+		logrus.Debugf("healthcheck for %s transitioned from %q to %q", c.ID(), previousStatus, healthCheck.Status)
+		c.newContainerHealthCheckEvent(healthCheck.Status)
+
+		if healthCheck.Status == HealthCheckUnhealthy {
+			if err := c.runHealthCheckOnFailureAction(); err != nil {
+				logrus.Errorf("running health check on-failure action for %s: %v", c.ID(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newContainerHealthCheckEvent emits a health_status event into libpod's
+// event backend so that `podman events` consumers can observe the
+// container's healthcheck state transitions
+func (c *Container) newContainerHealthCheckEvent(status string) {
+	e := events.NewEvent(events.HealthStatus)
+	e.Type = events.Container
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Image = c.config.RootfsImageName
+	e.HealthStatus = status
+	if err := c.runtime.eventer.Write(e); err != nil {
+		logrus.Errorf("unable to write health_status event for %s: %v", c.ID(), err)
+	}
+}
+
+// runHealthCheckOnFailureAction executes the configured OnFailure action
+// once a container's healthcheck has flipped it to unhealthy. It is called
+// directly from updateHealthCheckLog and therefore must not re-enter the
+// healthcheck timer.
+func (c *Container) runHealthCheckOnFailureAction() error {
+	switch c.HealthCheckOnFailureAction() {
+	case HealthCheckOnFailureActionNone:
+		return nil
+	case HealthCheckOnFailureActionKill:
+		logrus.Warnf("healthcheck for container %s has failed; killing the container", c.ID())
+		return c.Kill(uint(unix.SIGKILL))
+	case HealthCheckOnFailureActionStop:
+		logrus.Warnf("healthcheck for container %s has failed; stopping the container", c.ID())
+		return c.Stop()
+	case HealthCheckOnFailureActionRestart:
+		logrus.Warnf("healthcheck for container %s has failed; restarting the container", c.ID())
+		return c.RestartWithTimeout(context.Background(), c.config.StopTimeout)
+	case HealthCheckOnFailureActionExec:
+		logrus.Warnf("healthcheck for container %s has failed; running on-failure exec command", c.ID())
+		return c.execHealthCheckOnFailureCommand()
+	default:
+		return errors.Errorf("unknown health check on-failure action %d for %s", c.HealthCheckOnFailureAction(), c.ID())
+	}
+}
+
+// HealthCheckOnFailureAction returns the action configured to run once the
+// container's healthcheck reaches its failing-streak threshold
+func (c *Container) HealthCheckOnFailureAction() HealthCheckOnFailureAction {
+	return c.config.HealthCheckOnFailureAction
 }
 
 // HealthCheckLogPath returns the path for where the health check log is
@@ -228,12 +624,17 @@ func (c *Container) healthCheckLogPath() string {
 // an empty healthcheck struct is returned
 func (c *Container) GetHealthCheckLog() (inspect.HealthCheckResults, error) {
 	var healthCheck inspect.HealthCheckResults
-	if _, err := os.Stat(c.healthCheckLogPath()); os.IsNotExist(err) {
-		return healthCheck, nil
-	}
-	b, err := ioutil.ReadFile(c.healthCheckLogPath())
-	if err != nil {
-		return healthCheck, errors.Wrapf(err, "failed to read health check log file %s", c.healthCheckLogPath())
+
+	b, ok := defaultHealthCheckLogWriter.read(c.healthCheckLogPath())
+	if !ok {
+		if _, err := os.Stat(c.healthCheckLogPath()); os.IsNotExist(err) {
+			return healthCheck, nil
+		}
+		var err error
+		b, err = ioutil.ReadFile(c.healthCheckLogPath())
+		if err != nil {
+			return healthCheck, errors.Wrapf(err, "failed to read health check log file %s", c.healthCheckLogPath())
+		}
 	}
 	if err := json.Unmarshal(b, &healthCheck); err != nil {
 		return healthCheck, errors.Wrapf(err, "failed to unmarshal existing healthcheck results in %s", c.healthCheckLogPath())
@@ -241,60 +642,36 @@ func (c *Container) GetHealthCheckLog() (inspect.HealthCheckResults, error) {
 	return healthCheck, nil
 }
 
-// createTimer systemd timers for healthchecks of a container
+// createTimer schedules healthchecks for the container with the runtime's
+// configured HealthCheckScheduler (the in-process Go scheduler by default,
+// or systemd transient timers when opted into via containers.conf)
 func (c *Container) createTimer() error {
-	if c.disableHealthCheckSystemd() {
+	if c.config.HealthCheckConfig.Interval == 0 {
 		return nil
 	}
-	podman, err := os.Executable()
-	if err != nil {
-		return errors.Wrapf(err, "failed to get path for podman for a health check timer")
-	}
-
-	var cmd = []string{"--unit", fmt.Sprintf("%s", c.ID()), fmt.Sprintf("--on-unit-inactive=%s", c.HealthCheckConfig().Interval.String()), "--timer-property=AccuracySec=1s", podman, "healthcheck", "run", c.ID()}
-
-	conn, err := dbus.NewSystemdConnection()
-	if err != nil {
-		return errors.Wrapf(err, "unable to get systemd connection to add healthchecks")
-	}
-	conn.Close()
-	logrus.Debugf("creating systemd-transient files: %s %s", "systemd-run", cmd)
-	systemdRun := exec.Command("systemd-run", cmd...)
-	_, err = systemdRun.CombinedOutput()
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.runtime.healthCheckScheduler.Add(c)
 }
 
-// startTimer starts a systemd timer for the healthchecks
+// startTimer starts healthcheck scheduling for the container, e.g. right
+// after the container itself has started
 func (c *Container) startTimer() error {
-	if c.disableHealthCheckSystemd() {
+	if c.config.HealthCheckConfig.Interval == 0 {
 		return nil
 	}
-	conn, err := dbus.NewSystemdConnection()
-	if err != nil {
-		return errors.Wrapf(err, "unable to get systemd connection to start healthchecks")
-	}
-	defer conn.Close()
-	_, err = conn.StartUnit(fmt.Sprintf("%s.service", c.ID()), "fail", nil)
-	return err
+	return c.runtime.healthCheckScheduler.Start(c.ID())
 }
 
-// removeTimer removes the systemd timer and unit files
-// for the container
+// removeTimer stops healthcheck scheduling for the container and drops its
+// Prometheus time series, since container removal is the last point at
+// which its ID is still meaningful to callers
 func (c *Container) removeTimer() error {
-	if c.disableHealthCheckSystemd() {
-		return nil
+	if c.HasHealthCheck() {
+		cleanupHealthCheckMetrics(c)
 	}
-	conn, err := dbus.NewSystemdConnection()
-	if err != nil {
-		return errors.Wrapf(err, "unable to get systemd connection to remove healthchecks")
+	if c.config.HealthCheckConfig.Interval == 0 {
+		return nil
 	}
-	defer conn.Close()
-	serviceFile := fmt.Sprintf("%s.timer", c.ID())
-	_, err = conn.StopUnit(serviceFile, "fail", nil)
-	return err
+	return c.runtime.healthCheckScheduler.Remove(c.ID())
 }
 
 // HealthCheckStatus returns the current state of a container with a healthcheck
@@ -308,13 +685,3 @@ func (c *Container) HealthCheckStatus() (string, error) {
 	}
 	return results.Status, nil
 }
-
-func (c *Container) disableHealthCheckSystemd() bool {
-	if os.Getenv("DISABLE_HC_SYSTEMD") == "true" {
-		return true
-	}
-	if c.config.HealthCheckConfig.Interval == 0 {
-		return true
-	}
-	return false
-}
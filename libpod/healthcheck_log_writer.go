@@ -0,0 +1,68 @@
+package libpod
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// healthCheckLogWriter coalesces writes to containers' healthcheck.log
+// files, flushing each one to disk at most once per interval. Without it,
+// a worker pool running many healthchecks concurrently would otherwise
+// serialize on a synchronous disk write per execution.
+type healthCheckLogWriter struct {
+	mu       sync.Mutex
+	pending  map[string][]byte
+	flushing map[string]bool
+	interval time.Duration
+}
+
+// defaultHealthCheckLogWriter is shared by every container; healthcheck.log
+// paths are unique per container, so a single writer is sufficient
+var defaultHealthCheckLogWriter = newHealthCheckLogWriter(2 * time.Second)
+
+func newHealthCheckLogWriter(interval time.Duration) *healthCheckLogWriter {
+	return &healthCheckLogWriter{
+		pending:  make(map[string][]byte),
+		flushing: make(map[string]bool),
+		interval: interval,
+	}
+}
+
+// write queues data to be written to path, flushing it to disk at most once
+// per the writer's configured interval
+func (w *healthCheckLogWriter) write(path string, data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[path] = data
+	if w.flushing[path] {
+		return
+	}
+	w.flushing[path] = true
+	go w.flushAfter(path)
+}
+
+// read returns the not-yet-flushed contents queued for path, if any, so
+// readers observe the latest healthcheck results even before they hit disk
+func (w *healthCheckLogWriter) read(path string) ([]byte, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, ok := w.pending[path]
+	return data, ok
+}
+
+func (w *healthCheckLogWriter) flushAfter(path string) {
+	time.Sleep(w.interval)
+
+	w.mu.Lock()
+	data := w.pending[path]
+	delete(w.pending, path)
+	delete(w.flushing, path)
+	w.mu.Unlock()
+
+	if err := ioutil.WriteFile(path, data, 0700); err != nil {
+		logrus.Errorf("unable to flush healthcheck log %s: %v", path, err)
+	}
+}
@@ -0,0 +1,107 @@
+package libpod
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus collectors exporting per-container healthcheck results, hooked
+// into runHealthCheck and updateHealthCheckLog so that every execution keeps
+// them current without an external sidecar
+var (
+	healthCheckStatusGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "podman_healthcheck_status",
+			Help: "Current healthcheck status of a container (1 = healthy, 0 = unhealthy)",
+		},
+		[]string{"container", "name"},
+	)
+	healthCheckDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "podman_healthcheck_duration_seconds",
+			Help: "Duration of container healthcheck executions",
+		},
+		[]string{"container", "name"},
+	)
+	healthCheckFailingStreak = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "podman_healthcheck_failing_streak",
+			Help: "Current consecutive healthcheck failure count for a container",
+		},
+		[]string{"container", "name"},
+	)
+	healthCheckRunsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "podman_healthcheck_runs_total",
+			Help: "Total number of healthcheck runs by result",
+		},
+		[]string{"container", "name", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(healthCheckStatusGauge, healthCheckDurationSeconds, healthCheckFailingStreak, healthCheckRunsTotal)
+}
+
+// HealthCheckMetricsHandler returns the http.Handler exposing the
+// healthcheck metrics above in the Prometheus exposition format. `podman
+// system service` mounts it on its configurable metrics route via
+// RegisterHealthCheckMetricsRoute.
+func HealthCheckMetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterHealthCheckMetricsRoute mounts the healthcheck metrics handler on
+// mux at route, falling back to the default /metrics path if route is
+// empty. `podman system service` calls this with
+// runtime.config.Engine.HealthCheckMetricsRoute when it builds its API mux,
+// elsewhere in the repo and not part of this checkout.
+func RegisterHealthCheckMetricsRoute(mux *http.ServeMux, route string) {
+	if route == "" {
+		route = "/metrics"
+	}
+	mux.Handle(route, HealthCheckMetricsHandler())
+}
+
+// recordHealthCheckRun updates the duration and run-count metrics for a
+// single healthcheck execution
+func recordHealthCheckRun(c *Container, result HealthCheckStatus, duration time.Duration) {
+	id, name := c.ID(), c.Name()
+	healthCheckDurationSeconds.WithLabelValues(id, name).Observe(duration.Seconds())
+
+	label := "success"
+	if result != HealthCheckSuccess {
+		label = "failure"
+	}
+	healthCheckRunsTotal.WithLabelValues(id, name, label).Inc()
+}
+
+// recordHealthCheckStatus updates the status and failing-streak gauges
+// whenever the healthcheck log is refreshed
+func recordHealthCheckStatus(c *Container, status string, failingStreak int) {
+	id, name := c.ID(), c.Name()
+	value := 0.0
+	if status == HealthCheckHealthy {
+		value = 1.0
+	}
+	healthCheckStatusGauge.WithLabelValues(id, name).Set(value)
+	healthCheckFailingStreak.WithLabelValues(id, name).Set(float64(failingStreak))
+}
+
+// cleanupHealthCheckMetrics removes c's time series from the Prometheus
+// registry. It must run when a container is removed; otherwise the label
+// sets above grow without bound on exactly the container-churning hosts
+// this feature targets. Called from removeTimer, alongside scheduler
+// teardown.
+func cleanupHealthCheckMetrics(c *Container) {
+	id, name := c.ID(), c.Name()
+	healthCheckStatusGauge.DeleteLabelValues(id, name)
+	healthCheckFailingStreak.DeleteLabelValues(id, name)
+	healthCheckDurationSeconds.DeleteLabelValues(id, name)
+	for _, result := range []string{"success", "failure"} {
+		healthCheckRunsTotal.DeleteLabelValues(id, name, result)
+	}
+}
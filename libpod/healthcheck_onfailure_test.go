@@ -0,0 +1,45 @@
+package libpod
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHealthCheckOnFailureAction(t *testing.T) {
+	tests := []struct {
+		action      string
+		execCommand []string
+		want        HealthCheckOnFailureAction
+		wantCommand []string
+		wantErr     bool
+	}{
+		{action: "", want: HealthCheckOnFailureActionNone},
+		{action: "none", want: HealthCheckOnFailureActionNone},
+		{action: "kill", want: HealthCheckOnFailureActionKill},
+		{action: "stop", want: HealthCheckOnFailureActionStop},
+		{action: "restart", want: HealthCheckOnFailureActionRestart},
+		{action: "exec", execCommand: []string{"sh", "-c", "reload"}, want: HealthCheckOnFailureActionExec, wantCommand: []string{"sh", "-c", "reload"}},
+		{action: "exec", wantErr: true},
+		{action: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, gotCommand, err := ParseHealthCheckOnFailureAction(tt.action, tt.execCommand)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseHealthCheckOnFailureAction(%q) expected an error, got none", tt.action)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseHealthCheckOnFailureAction(%q) unexpected error: %v", tt.action, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseHealthCheckOnFailureAction(%q) = %v, want %v", tt.action, got, tt.want)
+		}
+		if !reflect.DeepEqual(gotCommand, tt.wantCommand) {
+			t.Errorf("ParseHealthCheckOnFailureAction(%q) command = %v, want %v", tt.action, gotCommand, tt.wantCommand)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package libpod
+
+import (
+	"sync"
+)
+
+// healthCheckWorkerPool runs container healthchecks with bounded
+// concurrency and deduplicates concurrent requests for the same container,
+// so that hosts with many containers don't serialize on a single check at
+// a time
+type healthCheckWorkerPool struct {
+	sem      chan struct{}
+	mu       sync.Mutex
+	inFlight map[string]*healthCheckCall
+}
+
+// healthCheckCall tracks a single in-flight healthcheck execution so that
+// callers racing on the same container ID can wait on and share its result
+type healthCheckCall struct {
+	done   chan struct{}
+	status HealthCheckStatus
+	err    error
+}
+
+// newHealthCheckWorkerPool creates a worker pool bounded to size concurrent
+// healthcheck executions. size is configured via containers.conf and
+// defaults to 1 (the previous, fully serial behavior) if unset.
+func newHealthCheckWorkerPool(size int) *healthCheckWorkerPool {
+	if size <= 0 {
+		size = 1
+	}
+	return &healthCheckWorkerPool{
+		sem:      make(chan struct{}, size),
+		inFlight: make(map[string]*healthCheckCall),
+	}
+}
+
+// run executes the container's healthcheck, coalescing concurrent callers
+// for the same container ID into a single execution whose result is shared
+func (p *healthCheckWorkerPool) run(c *Container) (HealthCheckStatus, error) {
+	p.mu.Lock()
+	if call, ok := p.inFlight[c.ID()]; ok {
+		p.mu.Unlock()
+		<-call.done
+		return call.status, call.err
+	}
+	call := &healthCheckCall{done: make(chan struct{})}
+	p.inFlight[c.ID()] = call
+	p.mu.Unlock()
+
+	p.sem <- struct{}{}
+	call.status, call.err = c.runHealthCheck()
+	<-p.sem
+
+	p.mu.Lock()
+	delete(p.inFlight, c.ID())
+	p.mu.Unlock()
+	close(call.done)
+
+	return call.status, call.err
+}
+
+// HealthCheckResult pairs a container with the outcome of its healthcheck,
+// as returned by Runtime.HealthCheckAll
+type HealthCheckResult struct {
+	ContainerID string
+	Status      HealthCheckStatus
+	Err         error
+}
+
+// HealthCheckAll fans out healthchecks across every container with a
+// defined healthcheck, routed through the runtime's healthcheck worker pool
+// to bound concurrency, and returns the aggregated results. This is useful
+// for the API service and for cluster-style health checking.
+func (r *Runtime) HealthCheckAll() ([]HealthCheckResult, error) {
+	containers, err := r.GetAllContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []HealthCheckResult
+	)
+	for _, container := range containers {
+		if !container.HasHealthCheck() {
+			continue
+		}
+		container := container
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := r.healthCheckPool.run(container)
+			mu.Lock()
+			results = append(results, HealthCheckResult{ContainerID: container.ID(), Status: status, Err: err})
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package libpod
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestHealthCheckProbeKind(t *testing.T) {
+	tests := []struct {
+		command []string
+		want    string
+	}{
+		{command: nil, want: ""},
+		{command: []string{"http", "GET", "http://:8080/healthz"}, want: "HTTP"},
+		{command: []string{"TCP", ":8080"}, want: "TCP"},
+		{command: []string{"grpc", ":8080", "myservice"}, want: "GRPC"},
+		{command: []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}, want: "CMD"},
+		{command: []string{"CMD", "curl", "-f", "http://localhost/"}, want: "CMD"},
+	}
+	for _, tt := range tests {
+		if got := healthCheckProbeKind(tt.command); got != tt.want {
+			t.Errorf("healthCheckProbeKind(%v) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteHealthCheckHostlessURL(t *testing.T) {
+	tests := []struct {
+		raw           string
+		containerAddr string
+		want          string
+	}{
+		{raw: "http://:8080/healthz", containerAddr: "10.0.0.5", want: "http://10.0.0.5:8080/healthz"},
+		{raw: "http://example.internal:8080/healthz", containerAddr: "10.0.0.5", want: "http://example.internal:8080/healthz"},
+	}
+	for _, tt := range tests {
+		target, err := url.Parse(tt.raw)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", tt.raw, err)
+		}
+		rewriteHealthCheckHostlessURL(target, tt.containerAddr)
+		if got := target.String(); got != tt.want {
+			t.Errorf("rewriteHealthCheckHostlessURL(%q, %q) = %q, want %q", tt.raw, tt.containerAddr, got, tt.want)
+		}
+	}
+}
+
+func TestParseHealthCheckHTTPHeaders(t *testing.T) {
+	headers, err := ParseHealthCheckHTTPHeaders([]string{"Host: example.internal", "X-Probe:  1 "})
+	if err != nil {
+		t.Fatalf("ParseHealthCheckHTTPHeaders: unexpected error: %v", err)
+	}
+	if got := headers.Get("Host"); got != "example.internal" {
+		t.Errorf("Host header = %q, want %q", got, "example.internal")
+	}
+	if got := headers.Get("X-Probe"); got != "1" {
+		t.Errorf("X-Probe header = %q, want %q", got, "1")
+	}
+
+	if _, err := ParseHealthCheckHTTPHeaders([]string{"no-colon-here"}); err == nil {
+		t.Errorf("ParseHealthCheckHTTPHeaders(%q) expected an error, got none", "no-colon-here")
+	}
+}
+
+func TestParseHealthCheckHTTPExpectedStatusRange(t *testing.T) {
+	tests := []struct {
+		statusRange string
+		wantMin     int
+		wantMax     int
+		wantErr     bool
+	}{
+		{statusRange: "", wantMin: 0, wantMax: 0},
+		{statusRange: "204", wantMin: 204, wantMax: 204},
+		{statusRange: "200-299", wantMin: 200, wantMax: 299},
+		{statusRange: "299-200", wantErr: true},
+		{statusRange: "bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		min, max, err := ParseHealthCheckHTTPExpectedStatusRange(tt.statusRange)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseHealthCheckHTTPExpectedStatusRange(%q) expected an error, got none", tt.statusRange)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseHealthCheckHTTPExpectedStatusRange(%q) unexpected error: %v", tt.statusRange, err)
+			continue
+		}
+		if min != tt.wantMin || max != tt.wantMax {
+			t.Errorf("ParseHealthCheckHTTPExpectedStatusRange(%q) = (%d, %d), want (%d, %d)", tt.statusRange, min, max, tt.wantMin, tt.wantMax)
+		}
+	}
+}
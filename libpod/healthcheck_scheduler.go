@@ -0,0 +1,294 @@
+package libpod
+
+import (
+	"container/heap"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HealthCheckScheduler schedules periodic execution of container
+// healthchecks. The default implementation, goHealthCheckScheduler, is an
+// in-process min-heap driven by a single goroutine; systemdHealthCheckScheduler
+// is kept as an opt-in backend for hosts that prefer systemd-managed timers
+type HealthCheckScheduler interface {
+	// Add (re)schedules healthchecks for the given container according
+	// to its configured interval
+	Add(c *Container) error
+	// Start begins running the healthcheck loop for the container, e.g.
+	// right after the container itself has started
+	Start(containerID string) error
+	// Remove stops scheduling healthchecks for the given container
+	Remove(containerID string) error
+}
+
+// schedulerEntry is a single container's position in the scheduler's
+// min-heap, ordered by nextRun
+type schedulerEntry struct {
+	containerID string
+	interval    time.Duration
+	nextRun     time.Time
+	index       int
+}
+
+// entryHeap implements container/heap.Interface over scheduler entries,
+// keeping the soonest nextRun at the root
+type entryHeap []*schedulerEntry
+
+func (h entryHeap) Len() int           { return len(h) }
+func (h entryHeap) Less(i, j int) bool { return h[i].nextRun.Before(h[j].nextRun) }
+func (h entryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entryHeap) Push(x interface{}) {
+	entry := x.(*schedulerEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*h = old[:n-1]
+	return entry
+}
+
+// goHealthCheckScheduler is the default HealthCheckScheduler. A single
+// goroutine waits on a time.Timer set to the next due entry in the heap,
+// runs whichever entries have come due, and reschedules them
+type goHealthCheckScheduler struct {
+	mu      sync.Mutex
+	heap    entryHeap
+	entries map[string]*schedulerEntry
+	wake    chan struct{}
+	runtime *Runtime
+}
+
+// newHealthCheckScheduler picks the HealthCheckScheduler backend configured
+// for the runtime. The in-process Go scheduler is the default; systemd
+// transient timers remain available as an opt-in backend for hosts that
+// already rely on them.
+func newHealthCheckScheduler(r *Runtime) HealthCheckScheduler {
+	if r.config.Engine.HealthCheckScheduler == "systemd" {
+		return newSystemdHealthCheckScheduler()
+	}
+	return newGoHealthCheckScheduler(r)
+}
+
+// newGoHealthCheckScheduler creates the in-process scheduler, reschedules
+// any container whose healthcheck was still pending a run before podman last
+// stopped, and starts the driver goroutine
+func newGoHealthCheckScheduler(r *Runtime) *goHealthCheckScheduler {
+	s := &goHealthCheckScheduler{
+		entries: make(map[string]*schedulerEntry),
+		wake:    make(chan struct{}, 1),
+		runtime: r,
+	}
+	heap.Init(&s.heap)
+	s.reload()
+	go s.run()
+	return s
+}
+
+// reload walks every running container with a healthcheck interval
+// configured and reschedules it, so that a podman restart resumes
+// healthcheck scheduling instead of silently dropping it. Containers that
+// were never scheduled (no persisted HealthCheckNextRun) are scheduled one
+// interval (plus jitter) from now, the same as a freshly started container.
+func (s *goHealthCheckScheduler) reload() {
+	containers, err := s.runtime.GetAllContainers()
+	if err != nil {
+		logrus.Errorf("healthcheck scheduler: unable to list containers to reload schedule: %v", err)
+		return
+	}
+	for _, c := range containers {
+		if !c.HasHealthCheck() || c.HealthCheckConfig().Interval <= 0 {
+			continue
+		}
+		state, err := c.State()
+		if err != nil || state != ContainerStateRunning {
+			continue
+		}
+		c.lock.Lock()
+		nextRun := c.state.HealthCheckNextRun
+		c.lock.Unlock()
+		if nextRun.IsZero() {
+			nextRun = time.Now().Add(c.HealthCheckConfig().Interval + jitter(c.HealthCheckConfig().Interval))
+		}
+		if err := s.schedule(c, nextRun); err != nil {
+			logrus.Errorf("healthcheck scheduler: unable to reschedule %s: %v", c.ID(), err)
+		}
+	}
+}
+
+// jitter returns a random duration in [0, interval/20) so that containers
+// sharing the same interval don't all run their healthcheck at once
+func jitter(interval time.Duration) time.Duration {
+	max := interval / 20
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func (s *goHealthCheckScheduler) Add(c *Container) error {
+	interval := c.HealthCheckConfig().Interval
+	if interval <= 0 {
+		return nil
+	}
+	return s.schedule(c, time.Now().Add(interval+jitter(interval)))
+}
+
+// schedule (re)inserts c into the heap with the given nextRun and persists
+// it to the container's state, sharing the bookkeeping that Add (and so
+// Start) and reload both need
+func (s *goHealthCheckScheduler) schedule(c *Container, nextRun time.Time) error {
+	interval := c.HealthCheckConfig().Interval
+
+	s.mu.Lock()
+	if entry, ok := s.entries[c.ID()]; ok {
+		entry.interval = interval
+		entry.nextRun = nextRun
+		heap.Fix(&s.heap, entry.index)
+	} else {
+		entry := &schedulerEntry{containerID: c.ID(), interval: interval, nextRun: nextRun}
+		s.entries[c.ID()] = entry
+		heap.Push(&s.heap, entry)
+	}
+	s.wakeup()
+	s.mu.Unlock()
+
+	persistHealthCheckNextRun(c, nextRun)
+	return nil
+}
+
+// persistHealthCheckNextRun takes the container's lock before mutating
+// c.state and calling c.save(), the same as any other write to a
+// container's state elsewhere in libpod. Without the lock, a
+// scheduler-driven run races unsynchronized against a manual healthcheck
+// run, a stop/start cycle, or anything else touching c.state concurrently.
+func persistHealthCheckNextRun(c *Container, nextRun time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.state.HealthCheckNextRun = nextRun
+	if err := c.save(); err != nil {
+		logrus.Errorf("unable to persist next healthcheck run time for %s: %v", c.ID(), err)
+	}
+}
+
+// Start (re)inserts the container into the scheduler, e.g. right after the
+// container itself has started. This is required, not just a convenience:
+// Remove deletes the container's entry outright on stop, so without Start
+// re-adding it, a stop/start cycle would silently end healthcheck
+// scheduling for the container for good.
+func (s *goHealthCheckScheduler) Start(containerID string) error {
+	c, err := s.runtime.LookupContainer(containerID)
+	if err != nil {
+		return err
+	}
+	return s.Add(c)
+}
+
+func (s *goHealthCheckScheduler) Remove(containerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[containerID]
+	if !ok {
+		return nil
+	}
+	delete(s.entries, containerID)
+	if entry.index >= 0 {
+		heap.Remove(&s.heap, entry.index)
+	}
+	return nil
+}
+
+func (s *goHealthCheckScheduler) run() {
+	for {
+		s.mu.Lock()
+		wait := time.Hour
+		if s.heap.Len() > 0 {
+			if d := time.Until(s.heap[0].nextRun); d > 0 {
+				wait = d
+			} else {
+				wait = 0
+			}
+		}
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+			s.runDue()
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// runDue pops every entry whose nextRun has passed and runs its
+// healthcheck, coalescing any runs that were missed (e.g. because podman
+// was not running) into a single catch-up run rather than bursting
+func (s *goHealthCheckScheduler) runDue() {
+	now := time.Now()
+	var due []*schedulerEntry
+	s.mu.Lock()
+	for s.heap.Len() > 0 && !s.heap[0].nextRun.After(now) {
+		due = append(due, heap.Pop(&s.heap).(*schedulerEntry))
+	}
+	s.mu.Unlock()
+
+	for _, entry := range due {
+		go s.runOne(entry)
+	}
+}
+
+func (s *goHealthCheckScheduler) runOne(entry *schedulerEntry) {
+	container, err := s.runtime.LookupContainer(entry.containerID)
+	if err != nil {
+		logrus.Debugf("healthcheck scheduler: container %s no longer exists, dropping its schedule", entry.containerID)
+		return
+	}
+	// Route through the runtime's healthcheck worker pool rather than
+	// calling container.runHealthCheck() directly: the pool bounds
+	// concurrency and, more importantly, deduplicates a scheduler-fired
+	// run against a concurrent manual "podman healthcheck run" for the
+	// same container. Without it, both would race unsynchronized through
+	// updateHealthCheckLog's read-modify-write of the healthcheck log.
+	if _, err := s.runtime.healthCheckPool.run(container); err != nil {
+		logrus.Debugf("healthcheck scheduler: healthcheck for %s failed: %v", entry.containerID, err)
+	}
+
+	s.mu.Lock()
+	if _, ok := s.entries[entry.containerID]; !ok {
+		// removed while the healthcheck was running
+		s.mu.Unlock()
+		return
+	}
+	next := entry.nextRun.Add(entry.interval + jitter(entry.interval))
+	for next.Before(time.Now()) {
+		next = next.Add(entry.interval)
+	}
+	entry.nextRun = next
+	heap.Push(&s.heap, entry)
+	s.wakeup()
+	s.mu.Unlock()
+
+	persistHealthCheckNextRun(container, next)
+}
+
+func (s *goHealthCheckScheduler) wakeup() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,65 @@
+package libpod
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/coreos/go-systemd/dbus"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// systemdHealthCheckScheduler schedules healthchecks via per-container
+// systemd transient timer units. It is kept as an opt-in HealthCheckScheduler
+// backend for hosts that prefer systemd-managed timers over the default
+// in-process goHealthCheckScheduler.
+type systemdHealthCheckScheduler struct{}
+
+// newSystemdHealthCheckScheduler returns the systemd-backed HealthCheckScheduler
+func newSystemdHealthCheckScheduler() *systemdHealthCheckScheduler {
+	return &systemdHealthCheckScheduler{}
+}
+
+// Add creates a systemd timer and service unit that invokes
+// `podman healthcheck run` for the container on its configured interval
+func (s *systemdHealthCheckScheduler) Add(c *Container) error {
+	podman, err := os.Executable()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get path for podman for a health check timer")
+	}
+
+	cmd := []string{"--unit", fmt.Sprintf("%s", c.ID()), fmt.Sprintf("--on-unit-inactive=%s", c.HealthCheckConfig().Interval.String()), "--timer-property=AccuracySec=1s", podman, "healthcheck", "run", c.ID()}
+
+	conn, err := dbus.NewSystemdConnection()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get systemd connection to add healthchecks")
+	}
+	conn.Close()
+	logrus.Debugf("creating systemd-transient files: %s %s", "systemd-run", cmd)
+	systemdRun := exec.Command("systemd-run", cmd...)
+	_, err = systemdRun.CombinedOutput()
+	return err
+}
+
+// Start starts the systemd unit backing the container's healthcheck timer
+func (s *systemdHealthCheckScheduler) Start(containerID string) error {
+	conn, err := dbus.NewSystemdConnection()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get systemd connection to start healthchecks")
+	}
+	defer conn.Close()
+	_, err = conn.StartUnit(fmt.Sprintf("%s.service", containerID), "fail", nil)
+	return err
+}
+
+// Remove stops the systemd timer and unit files for the container
+func (s *systemdHealthCheckScheduler) Remove(containerID string) error {
+	conn, err := dbus.NewSystemdConnection()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get systemd connection to remove healthchecks")
+	}
+	defer conn.Close()
+	_, err = conn.StopUnit(fmt.Sprintf("%s.timer", containerID), "fail", nil)
+	return err
+}
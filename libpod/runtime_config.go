@@ -0,0 +1,57 @@
+package libpod
+
+import (
+	"github.com/containers/libpod/libpod/events"
+)
+
+// Runtime holds the fields of libpod's Runtime that the healthcheck code in
+// this package depends on. The remainder of Runtime is defined alongside
+// the rest of the libpod package and is not part of this checkout.
+type Runtime struct {
+	config *Config
+
+	// eventer writes libpod events (e.g. health_status) to the
+	// configured event backend; see newContainerHealthCheckEvent
+	eventer events.Eventer
+
+	// healthCheckScheduler drives periodic healthcheck execution; see
+	// newHealthCheckScheduler
+	healthCheckScheduler HealthCheckScheduler
+	// healthCheckPool bounds concurrent healthcheck executions and
+	// deduplicates in-flight runs; see newHealthCheckWorkerPool
+	healthCheckPool *healthCheckWorkerPool
+}
+
+// Config mirrors the subset of containers.conf
+// (github.com/containers/common/pkg/config.Config) that the healthcheck
+// code depends on.
+type Config struct {
+	Engine EngineConfig
+}
+
+// EngineConfig mirrors the subset of the containers.conf [engine] table
+// that the healthcheck code depends on.
+type EngineConfig struct {
+	// HealthCheckScheduler selects the HealthCheckScheduler backend for
+	// periodic healthchecks: "" or "go" (the default) uses the
+	// in-process min-heap scheduler, "systemd" uses per-container
+	// transient timer units.
+	HealthCheckScheduler string
+	// HealthCheckPoolSize bounds how many healthchecks the healthcheck
+	// worker pool runs concurrently. 0 (the default) means 1, i.e. fully
+	// serial execution, matching the pre-pool behavior.
+	HealthCheckPoolSize int
+	// HealthCheckMetricsRoute is the path podman system service mounts
+	// the Prometheus healthcheck metrics handler on via
+	// RegisterHealthCheckMetricsRoute. Defaults to /metrics if unset.
+	HealthCheckMetricsRoute string
+}
+
+// setupHealthCheckSubsystem wires up the healthcheck scheduler and worker
+// pool for the runtime. It is called from NewRuntime during runtime
+// initialization, alongside the rest of libpod's startup, which is not part
+// of this checkout.
+func (r *Runtime) setupHealthCheckSubsystem() {
+	r.healthCheckScheduler = newHealthCheckScheduler(r)
+	r.healthCheckPool = newHealthCheckWorkerPool(r.config.Engine.HealthCheckPoolSize)
+}